@@ -0,0 +1,63 @@
+package structscanner
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ConverterFunc converts a raw decoded value (whatever a TagDecoder
+// returned) into a reflect.Value assignable to the type it was registered
+// for.
+type ConverterFunc func(src interface{}) (reflect.Value, error)
+
+// ConverterRegistry maps a reflect.Type to the ConverterFunc responsible
+// for producing values of that type, letting Decode handle types its
+// generic reflect-based assignment can't, such as time.Time, net.IP, or
+// user-defined enums.
+//
+// The zero value is not usable; build one with NewConverterRegistry.
+type ConverterRegistry struct {
+	mu         sync.RWMutex
+	converters map[reflect.Type]ConverterFunc
+}
+
+// NewConverterRegistry returns an empty, ready to use ConverterRegistry.
+func NewConverterRegistry() *ConverterRegistry {
+	return &ConverterRegistry{
+		converters: map[reflect.Type]ConverterFunc{},
+	}
+}
+
+// DefaultConverterRegistry is the registry Decode consults when no
+// WithConverterRegistry option is passed. RegisterConverter registers into
+// this registry.
+var DefaultConverterRegistry = NewConverterRegistry()
+
+// RegisterConverter registers convert on the DefaultConverterRegistry for
+// typ, e.g.:
+//
+//	structscanner.RegisterConverter(reflect.TypeOf(time.Time{}), func(src interface{}) (reflect.Value, error) {
+//		t, err := time.Parse(time.RFC3339, src.(string))
+//		return reflect.ValueOf(t), err
+//	})
+func RegisterConverter(typ reflect.Type, convert ConverterFunc) {
+	DefaultConverterRegistry.Register(typ, convert)
+}
+
+// Register registers convert on r for typ.
+func (r *ConverterRegistry) Register(typ reflect.Type, convert ConverterFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.converters[typ] = convert
+}
+
+func (r *ConverterRegistry) lookup(typ reflect.Type) (ConverterFunc, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	convert, ok := r.converters[typ]
+	return convert, ok
+}
@@ -0,0 +1,95 @@
+package structscanner_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	ss "github.com/vingarcia/structscanner"
+	tt "github.com/vingarcia/structscanner/internal/testtools"
+)
+
+func TestConverterRegistry(t *testing.T) {
+	timeConverter := func(src interface{}) (reflect.Value, error) {
+		str, ok := src.(string)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected a string, got: %T", src)
+		}
+
+		parsed, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(parsed), nil
+	}
+
+	t.Run("should use a registered converter instead of the generic conversion logic", func(t *testing.T) {
+		registry := ss.NewConverterRegistry()
+		registry.Register(reflect.TypeOf(time.Time{}), timeConverter)
+
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "2022-01-02T15:04:05Z", nil
+		})
+
+		var output struct {
+			Attr1 time.Time `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder, ss.WithConverterRegistry(registry))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC))
+	})
+
+	t.Run("should also convert slice elements using the registry", func(t *testing.T) {
+		registry := ss.NewConverterRegistry()
+		registry.Register(reflect.TypeOf(time.Time{}), timeConverter)
+
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return []string{"2022-01-02T15:04:05Z", "2023-05-06T07:08:09Z"}, nil
+		})
+
+		var output struct {
+			Attr1 []time.Time `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder, ss.WithConverterRegistry(registry))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, []time.Time{
+			time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC),
+			time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC),
+		})
+	})
+
+	t.Run("should wrap the error returned by a converter", func(t *testing.T) {
+		registry := ss.NewConverterRegistry()
+		registry.Register(reflect.TypeOf(time.Time{}), timeConverter)
+
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "not-a-time", nil
+		})
+
+		var output struct {
+			Attr1 time.Time `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder, ss.WithConverterRegistry(registry))
+		tt.AssertErrContains(t, err, "Attr1")
+	})
+
+	t.Run("should use the default registry when no option is passed", func(t *testing.T) {
+		type myID string
+
+		ss.RegisterConverter(reflect.TypeOf(myID("")), func(src interface{}) (reflect.Value, error) {
+			return reflect.ValueOf(myID(fmt.Sprintf("id-%v", src))), nil
+		})
+
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return 42, nil
+		})
+
+		var output struct {
+			Attr1 myID `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, myID("id-42"))
+	})
+}
@@ -0,0 +1,149 @@
+package structscanner_test
+
+import (
+	"errors"
+	"testing"
+
+	ss "github.com/vingarcia/structscanner"
+	tt "github.com/vingarcia/structscanner/internal/testtools"
+)
+
+func TestWithRequireAllFields(t *testing.T) {
+	t.Run("should error if a field is left untouched", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			if field.Name == "Attr1" {
+				return "value1", nil
+			}
+			return nil, nil
+		})
+
+		var output struct {
+			Attr1 string `env:"attr1"`
+			Attr2 string `env:"attr2"`
+		}
+		err := ss.Decode(&output, decoder, ss.WithRequireAllFields())
+		tt.AssertErrContains(t, err, "Attr2")
+	})
+
+	t.Run("should not error when every field is filled", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "value", nil
+		})
+
+		var output struct {
+			Attr1 string `env:"attr1"`
+			Attr2 string `env:"attr2"`
+		}
+		err := ss.Decode(&output, decoder, ss.WithRequireAllFields())
+		tt.AssertNoErr(t, err)
+	})
+
+	t.Run("should error if a slice of structs has no elements", func(t *testing.T) {
+		type User struct {
+			Name string `env:"name"`
+		}
+
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return nil, nil
+		})
+
+		var output struct {
+			Users []User
+		}
+		err := ss.Decode(&output, decoder, ss.WithRequireAllFields())
+		tt.AssertErrContains(t, err, "Users")
+	})
+}
+
+func TestWithZeroEmpty(t *testing.T) {
+	t.Run("should zero a field when the decoder returns an empty string", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "", nil
+		})
+
+		output := struct {
+			Attr1 string `env:"attr1"`
+		}{
+			Attr1: "previous value",
+		}
+		err := ss.Decode(&output, decoder, ss.WithZeroEmpty())
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, "")
+	})
+
+	t.Run("should zero a slice field when the decoder returns an empty slice", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return []interface{}{}, nil
+		})
+
+		output := struct {
+			Attr1 []string `env:"attr1"`
+		}{
+			Attr1: []string{"a", "b"},
+		}
+		err := ss.Decode(&output, decoder, ss.WithZeroEmpty())
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, []string(nil))
+	})
+
+	t.Run("should not affect non-empty values", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "value", nil
+		})
+
+		var output struct {
+			Attr1 string `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder, ss.WithZeroEmpty())
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, "value")
+	})
+}
+
+func TestWithIgnoreDecodeErrors(t *testing.T) {
+	t.Run("should collect every field error instead of aborting on the first one", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return []int{1, 2, 3}, nil
+		})
+
+		var output struct {
+			Attr1 string `env:"attr1"`
+			Attr2 string `env:"attr2"`
+		}
+		err := ss.Decode(&output, decoder, ss.WithIgnoreDecodeErrors())
+		tt.AssertErrContains(t, err, "Attr1", "Attr2")
+
+		var multiErr interface{ Unwrap() []error }
+		tt.AssertTrue(t, errors.As(err, &multiErr))
+		tt.AssertEqual(t, len(multiErr.Unwrap()), 2)
+	})
+
+	t.Run("should still decode the fields that don't error", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			if field.Name == "Attr1" {
+				return []int{1, 2, 3}, nil
+			}
+			return "value2", nil
+		})
+
+		var output struct {
+			Attr1 string `env:"attr1"`
+			Attr2 string `env:"attr2"`
+		}
+		err := ss.Decode(&output, decoder, ss.WithIgnoreDecodeErrors())
+		tt.AssertErrContains(t, err, "Attr1")
+		tt.AssertEqual(t, output.Attr2, "value2")
+	})
+
+	t.Run("should return nil when no field errors", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "value", nil
+		})
+
+		var output struct {
+			Attr1 string `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder, ss.WithIgnoreDecodeErrors())
+		tt.AssertNoErr(t, err)
+	})
+}
@@ -0,0 +1,76 @@
+package structscanner
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TagEncoder encodes the value of a single struct field.
+//
+// Returning a non-nil TagEncoder for a struct (or pointer-to-struct) field
+// makes Encode recurse into it using the returned encoder, symmetrically to
+// how TagDecoder recurses in Decode. Returning (nil, nil) for any other
+// field means the value was consumed and Encode moves on to the next one.
+type TagEncoder interface {
+	EncodeField(field Field, value interface{}) (TagEncoder, error)
+}
+
+// FuncTagEncoder adapts a plain function into a TagEncoder.
+type FuncTagEncoder func(field Field, value interface{}) (TagEncoder, error)
+
+// EncodeField implements the TagEncoder interface.
+func (f FuncTagEncoder) EncodeField(field Field, value interface{}) (TagEncoder, error) {
+	return f(field, value)
+}
+
+// Encode walks the exported fields of the struct pointed to by input,
+// calling encoder.EncodeField once for each field so encoder can push the
+// value into whatever sink it wraps (an env writer, a url.Values, a
+// map[string]interface{}, ...).
+//
+// A nil pointer to a struct field is reported to encoder as a nil value and
+// is never recursed into. Any other struct (or pointer-to-struct) field is
+// offered to encoder first; if it returns a TagEncoder, Encode recurses
+// into that field using it, exactly mirroring Decode's nested behavior.
+func Encode(input interface{}, encoder TagEncoder) error {
+	structInfo, err := GetStructInfo(input)
+	if err != nil {
+		return err
+	}
+
+	structVal := reflect.ValueOf(input).Elem()
+	for _, field := range structInfo.Fields {
+		fieldVal := structVal.FieldByIndex(field.Index)
+
+		isNilPtr := fieldVal.Kind() == reflect.Ptr && fieldVal.IsNil()
+		isNilStructPtr := isNilPtr && fieldVal.Type().Elem().Kind() == reflect.Struct
+
+		var value interface{}
+		if !isNilPtr {
+			if fieldVal.Kind() == reflect.Ptr {
+				value = fieldVal.Elem().Interface()
+			} else {
+				value = fieldVal.Interface()
+			}
+		}
+
+		nestedEncoder, err := encoder.EncodeField(field, value)
+		if err != nil {
+			return fmt.Errorf("error encoding field %s: %w", field.Name, err)
+		}
+		if nestedEncoder == nil || isNilStructPtr {
+			continue
+		}
+
+		target := fieldVal.Addr()
+		if fieldVal.Kind() == reflect.Ptr {
+			target = fieldVal
+		}
+
+		if err := Encode(target.Interface(), nestedEncoder); err != nil {
+			return fmt.Errorf("error encoding nested field %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package structscanner_test
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	ss "github.com/vingarcia/structscanner"
+	tt "github.com/vingarcia/structscanner/internal/testtools"
+)
+
+var errBoom = errors.New("boom")
+
+func TestEncode(t *testing.T) {
+	t.Run("should encode a single tagged field", func(t *testing.T) {
+		var got []string
+		encoder := ss.FuncTagEncoder(func(field ss.Field, value interface{}) (ss.TagEncoder, error) {
+			got = append(got, field.Tags["env"], value.(string))
+			return nil, nil
+		})
+
+		input := struct {
+			Attr1 string `env:"attr1"`
+		}{
+			Attr1: "some-value",
+		}
+		err := ss.Encode(&input, encoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, got, []string{"attr1", "some-value"})
+	})
+
+	t.Run("should ignore private fields", func(t *testing.T) {
+		var calls int
+		encoder := ss.FuncTagEncoder(func(field ss.Field, value interface{}) (ss.TagEncoder, error) {
+			calls++
+			return nil, nil
+		})
+
+		input := struct {
+			Attr1 string `env:"attr1"`
+			attr2 string
+		}{
+			Attr1: "v1",
+			attr2: "v2",
+		}
+		err := ss.Encode(&input, encoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, calls, 1)
+	})
+
+	t.Run("should recurse into nested structs when a TagEncoder is returned", func(t *testing.T) {
+		var outerSeen, innerSeen bool
+		var innerEncoder ss.TagEncoder
+		innerEncoder = ss.FuncTagEncoder(func(field ss.Field, value interface{}) (ss.TagEncoder, error) {
+			innerSeen = true
+			tt.AssertEqual(t, value, 42)
+			return nil, nil
+		})
+
+		encoder := ss.FuncTagEncoder(func(field ss.Field, value interface{}) (ss.TagEncoder, error) {
+			if field.Kind == reflect.Struct {
+				return innerEncoder, nil
+			}
+			outerSeen = true
+			return nil, nil
+		})
+
+		var input struct {
+			Attr1       int `env:"attr1"`
+			OtherStruct struct {
+				Attr2 int `env:"attr2"`
+			}
+		}
+		input.Attr1 = 1
+		input.OtherStruct.Attr2 = 42
+
+		err := ss.Encode(&input, encoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertTrue(t, outerSeen, "expected the outer field to be visited")
+		tt.AssertTrue(t, innerSeen, "expected the nested field to be visited")
+	})
+
+	t.Run("should not recurse into nil struct pointers", func(t *testing.T) {
+		var calls int
+		encoder := ss.FuncTagEncoder(func(field ss.Field, value interface{}) (ss.TagEncoder, error) {
+			calls++
+			tt.AssertEqual(t, value, nil)
+			return ss.FuncTagEncoder(func(field ss.Field, value interface{}) (ss.TagEncoder, error) {
+				t.Fatalf("should not recurse into a nil struct pointer")
+				return nil, nil
+			}), nil
+		})
+
+		var input struct {
+			OtherStruct *struct {
+				Attr1 int `env:"attr1"`
+			}
+		}
+		err := ss.Encode(&input, encoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, calls, 1)
+	})
+
+	t.Run("should report nil for a nil pointer to a non-struct field", func(t *testing.T) {
+		encoder := ss.FuncTagEncoder(func(field ss.Field, value interface{}) (ss.TagEncoder, error) {
+			tt.AssertEqual(t, value, nil)
+			return nil, nil
+		})
+
+		var input struct {
+			Attr1 *int
+		}
+		err := ss.Encode(&input, encoder)
+		tt.AssertNoErr(t, err)
+	})
+
+	t.Run("wrap errors correctly", func(t *testing.T) {
+		encoder := ss.FuncTagEncoder(func(field ss.Field, value interface{}) (ss.TagEncoder, error) {
+			return nil, errBoom
+		})
+
+		err := ss.Encode(&struct {
+			A int `a:""`
+		}{}, encoder)
+		tt.AssertErrContains(t, err, "A", errBoom.Error())
+	})
+
+	t.Run("builtin encoders", func(t *testing.T) {
+		t.Run("MapEncoder should encode tagged fields into a map", func(t *testing.T) {
+			type Address struct {
+				City string `map:"city"`
+			}
+
+			input := struct {
+				Name    string  `map:"name"`
+				Address Address `map:"address"`
+			}{
+				Name:    "alice",
+				Address: Address{City: "nowhere"},
+			}
+
+			dst := map[string]interface{}{}
+			err := ss.Encode(&input, ss.MapEncoder(dst))
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, dst, map[string]interface{}{
+				"name": "alice",
+				"address": map[string]interface{}{
+					"city": "nowhere",
+				},
+			})
+		})
+
+		t.Run("URLValuesEncoder should encode tagged fields as strings", func(t *testing.T) {
+			type Address struct {
+				City string `url:"city"`
+			}
+
+			input := struct {
+				Age     int     `url:"age"`
+				Address Address `url:"address"`
+			}{
+				Age:     30,
+				Address: Address{City: "nowhere"},
+			}
+
+			dst := url.Values{}
+			err := ss.Encode(&input, ss.URLValuesEncoder(dst))
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, dst.Get("age"), "30")
+			tt.AssertEqual(t, dst.Get("address.city"), "nowhere")
+		})
+	})
+}
@@ -0,0 +1,66 @@
+// Package testtools provides small test assertion helpers shared by the
+// structscanner test suite.
+package testtools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// AssertNoErr fails the test if err is not nil.
+func AssertNoErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+}
+
+// AssertEqual fails the test if got and want are not deeply equal.
+func AssertEqual(t *testing.T, got, want interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("values are not equal:\n  got:  %#v\n  want: %#v", got, want)
+	}
+}
+
+// AssertNotEqual fails the test if got and want are deeply equal.
+func AssertNotEqual(t *testing.T, got, want interface{}) {
+	t.Helper()
+	if reflect.DeepEqual(got, want) {
+		t.Fatalf("expected values to differ, got: %#v", got)
+	}
+}
+
+// AssertTrue fails the test if cond is false, formatting msgAndArgs as the
+// failure message when provided.
+func AssertTrue(t *testing.T, cond bool, msgAndArgs ...interface{}) {
+	t.Helper()
+	if cond {
+		return
+	}
+
+	if len(msgAndArgs) == 0 {
+		t.Fatalf("expected condition to be true")
+		return
+	}
+
+	format, _ := msgAndArgs[0].(string)
+	t.Fatalf(format, msgAndArgs[1:]...)
+}
+
+// AssertErrContains fails the test if err is nil or if its message does not
+// contain every string in substrs.
+func AssertErrContains(t *testing.T, err error, substrs ...string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected an error containing %v, got nil", substrs)
+	}
+
+	msg := err.Error()
+	for _, s := range substrs {
+		if !strings.Contains(msg, s) {
+			t.Fatalf("expected error message %q to contain %q", msg, s)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package structscanner
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metadata reports, after a MapDecoder-driven Decode call returns, which
+// source keys were consumed by a struct field and which were left over.
+type Metadata struct {
+	// Keys holds every dotted-path key (e.g. "user.address.city") that was
+	// consumed by a struct field, in sorted order.
+	Keys []string
+
+	// Unused holds every dotted-path key present in the source map that no
+	// field consumed, in sorted order.
+	Unused []string
+}
+
+// MapDecoderOption configures a MapDecoder.
+type MapDecoderOption func(*mapDecoderConfig)
+
+type mapDecoderConfig struct {
+	weaklyTyped   bool
+	caseSensitive bool
+	metadata      *Metadata
+}
+
+// WithWeaklyTypedInput makes the MapDecoder coerce between compatible
+// primitives (e.g. "42" -> int, 1 -> "1", "true" -> bool, a single value ->
+// a one-element slice) instead of handing the raw source value straight to
+// Decode's own, stricter, conversion logic.
+func WithWeaklyTypedInput() MapDecoderOption {
+	return func(c *mapDecoderConfig) {
+		c.weaklyTyped = true
+	}
+}
+
+// WithCaseSensitiveKeys makes the MapDecoder require an exact key match,
+// instead of its default case-insensitive lookup.
+func WithCaseSensitiveKeys() MapDecoderOption {
+	return func(c *mapDecoderConfig) {
+		c.caseSensitive = true
+	}
+}
+
+// WithMetadata makes the MapDecoder record, into metadata, which keys were
+// consumed and which were left over once decoding finishes.
+func WithMetadata(metadata *Metadata) MapDecoderOption {
+	return func(c *mapDecoderConfig) {
+		c.metadata = metadata
+	}
+}
+
+// mapDecoderState is shared by a MapDecoder and every child decoder it
+// recurses into, so consumption can be tracked across the whole tree.
+type mapDecoderState struct {
+	consumed map[string]bool
+	allKeys  []string
+}
+
+// MapDecoder returns a TagDecoder that reads from src, looking each field
+// up by its "map" tag (falling back to the field name when the tag is
+// absent). A dotted tag such as `map:"user.address.city"` walks nested
+// map[string]interface{} and []interface{} values by splitting on ".".
+// Struct (and pointer-to-struct) fields recurse into a child MapDecoder
+// bound to the matching sub-map. A []Struct (or []*Struct) field is filled
+// element by element from a []interface{} of maps found at its key, via
+// Decode's indexed-slice engine.
+func MapDecoder(src map[string]interface{}, opts ...MapDecoderOption) TagDecoder {
+	cfg := &mapDecoderConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	state := &mapDecoderState{consumed: map[string]bool{}}
+	if cfg.metadata != nil {
+		state.allKeys = flattenKeys(src, "")
+		updateMetadata(cfg.metadata, state)
+	}
+
+	return newMapDecoder(src, "", cfg, state)
+}
+
+func newMapDecoder(src map[string]interface{}, prefix string, cfg *mapDecoderConfig, state *mapDecoderState) TagDecoder {
+	return FuncTagDecoder(func(field Field) (interface{}, error) {
+		key := field.Tags["map"]
+		if key == "" {
+			key = field.Name
+		}
+
+		if field.SliceIndex >= 0 {
+			return decodeMapSliceElement(src, prefix, key, field, cfg, state)
+		}
+
+		if isSliceOfStructs(field.Type) {
+			// Mark the list itself consumed, even though Decode's
+			// indexed-slice engine is the one that will actually read its
+			// elements, by coming back and probing us with field.SliceIndex
+			// set to 0, 1, 2, ... instead.
+			if _, resolvedPath, found := lookupPath(src, key, !cfg.caseSensitive); found {
+				fullPath := resolvedPath
+				if prefix != "" {
+					fullPath = prefix + "." + resolvedPath
+				}
+				state.consumed[fullPath] = true
+				if cfg.metadata != nil {
+					updateMetadata(cfg.metadata, state)
+				}
+			}
+			return nil, nil
+		}
+
+		value, resolvedPath, found := lookupPath(src, key, !cfg.caseSensitive)
+		if !found {
+			return nil, nil
+		}
+
+		fullPath := resolvedPath
+		if prefix != "" {
+			fullPath = prefix + "." + resolvedPath
+		}
+		state.consumed[fullPath] = true
+		if cfg.metadata != nil {
+			updateMetadata(cfg.metadata, state)
+		}
+
+		if isStructOrPtrToStruct(field) {
+			sub, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected a nested map for field %s, got %T", field.Name, value)
+			}
+			return newMapDecoder(sub, fullPath, cfg, state), nil
+		}
+
+		if cfg.weaklyTyped {
+			value = weaklyCoerce(value, field.Type)
+		}
+
+		return value, nil
+	})
+}
+
+// decodeMapSliceElement looks up the field.SliceIndex-th element of the
+// []interface{} found at key, returning a child MapDecoder bound to it so
+// Decode's indexed-slice engine can fill a []Struct (or []*Struct) field
+// one element at a time. It returns (nil, nil) once field.SliceIndex runs
+// past the end of the list, telling Decode to stop probing.
+func decodeMapSliceElement(src map[string]interface{}, prefix, key string, field Field, cfg *mapDecoderConfig, state *mapDecoderState) (interface{}, error) {
+	value, resolvedPath, found := lookupPath(src, key, !cfg.caseSensitive)
+	if !found {
+		return nil, nil
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list for field %s, got %T", field.Name, value)
+	}
+	if field.SliceIndex >= len(list) {
+		return nil, nil
+	}
+
+	elem, ok := list[field.SliceIndex].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a nested map for field %s[%d], got %T", field.Name, field.SliceIndex, list[field.SliceIndex])
+	}
+
+	fullPath := fmt.Sprintf("%s.%d", resolvedPath, field.SliceIndex)
+	if prefix != "" {
+		fullPath = prefix + "." + fullPath
+	}
+	state.consumed[fullPath] = true
+	if cfg.metadata != nil {
+		updateMetadata(cfg.metadata, state)
+	}
+
+	return newMapDecoder(elem, fullPath, cfg, state), nil
+}
+
+// lookupPath walks src following the "."-separated segments of path,
+// descending into nested maps and, for numeric segments, slices. It
+// returns the looked up value, the path actually matched (which may differ
+// from path in case when lookups are case-insensitive), and whether it was
+// found at all.
+func lookupPath(src map[string]interface{}, path string, caseInsensitive bool) (interface{}, string, bool) {
+	var cur interface{} = src
+	var resolved []string
+
+	for _, part := range strings.Split(path, ".") {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, actualKey, ok := lookupKey(c, part, caseInsensitive)
+			if !ok {
+				return nil, "", false
+			}
+			cur = v
+			resolved = append(resolved, actualKey)
+
+		case []interface{}:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(c) {
+				return nil, "", false
+			}
+			cur = c[idx]
+			resolved = append(resolved, part)
+
+		default:
+			return nil, "", false
+		}
+	}
+
+	return cur, strings.Join(resolved, "."), true
+}
+
+func lookupKey(m map[string]interface{}, key string, caseInsensitive bool) (interface{}, string, bool) {
+	if v, ok := m[key]; ok {
+		return v, key, true
+	}
+	if !caseInsensitive {
+		return nil, "", false
+	}
+
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, k, true
+		}
+	}
+	return nil, "", false
+}
+
+// flattenKeys recursively lists every dotted-path key reachable in value
+// (a map[string]interface{} or []interface{}), including intermediate
+// ones, so Metadata.Unused can report keys left over at any depth.
+func flattenKeys(value interface{}, prefix string) []string {
+	var keys []string
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, sub := range v {
+			full := k
+			if prefix != "" {
+				full = prefix + "." + k
+			}
+			keys = append(keys, full)
+			keys = append(keys, flattenKeys(sub, full)...)
+		}
+
+	case []interface{}:
+		for i, sub := range v {
+			full := fmt.Sprintf("%s.%d", prefix, i)
+			keys = append(keys, full)
+			keys = append(keys, flattenKeys(sub, full)...)
+		}
+	}
+
+	return keys
+}
+
+func updateMetadata(metadata *Metadata, state *mapDecoderState) {
+	keys := make([]string, 0, len(state.consumed))
+	for k := range state.consumed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var unused []string
+	for _, k := range state.allKeys {
+		if !state.consumed[k] {
+			unused = append(unused, k)
+		}
+	}
+	sort.Strings(unused)
+
+	metadata.Keys = keys
+	metadata.Unused = unused
+}
+
+// weaklyCoerce converts value into a shape more likely to be accepted by
+// Decode's generic conversion logic for a field of destType, the way
+// mapstructure's WeaklyTypedInput option does: numeric strings become
+// numbers, numbers and bools become strings, and a single value becomes a
+// one-element slice when destType is a slice.
+func weaklyCoerce(value interface{}, destType reflect.Type) interface{} {
+	destKind := destType.Kind()
+	for destKind == reflect.Ptr {
+		destType = destType.Elem()
+		destKind = destType.Kind()
+	}
+
+	if str, ok := value.(string); ok {
+		switch destKind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+				return n
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if n, err := strconv.ParseUint(str, 10, 64); err == nil {
+				return n
+			}
+		case reflect.Float32, reflect.Float64:
+			if f, err := strconv.ParseFloat(str, 64); err == nil {
+				return f
+			}
+		case reflect.Bool:
+			if b, err := strconv.ParseBool(str); err == nil {
+				return b
+			}
+		}
+	} else if destKind == reflect.String && isWeaklyStringifiable(value) {
+		return fmt.Sprintf("%v", value)
+	}
+
+	if destKind == reflect.Slice && reflect.ValueOf(value).Kind() != reflect.Slice {
+		return []interface{}{value}
+	}
+
+	return value
+}
+
+func isWeaklyStringifiable(value interface{}) bool {
+	switch value.(type) {
+	case bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,248 @@
+package structscanner_test
+
+import (
+	"testing"
+
+	ss "github.com/vingarcia/structscanner"
+	tt "github.com/vingarcia/structscanner/internal/testtools"
+)
+
+func TestMapDecoder(t *testing.T) {
+	t.Run("should decode a field tagged with a dotted path", func(t *testing.T) {
+		src := map[string]interface{}{
+			"user": map[string]interface{}{
+				"address": map[string]interface{}{
+					"city": "Springfield",
+				},
+			},
+		}
+
+		var output struct {
+			City string `map:"user.address.city"`
+		}
+		err := ss.Decode(&output, ss.MapDecoder(src))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.City, "Springfield")
+	})
+
+	t.Run("should fall back to the field name, case-insensitively, when the tag is absent", func(t *testing.T) {
+		src := map[string]interface{}{
+			"Name": "Alice",
+		}
+
+		var output struct {
+			Name string
+		}
+		err := ss.Decode(&output, ss.MapDecoder(src))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Name, "Alice")
+	})
+
+	t.Run("should require an exact match with WithCaseSensitiveKeys", func(t *testing.T) {
+		src := map[string]interface{}{
+			"name": "Alice",
+		}
+
+		var output struct {
+			Name string
+		}
+		err := ss.Decode(&output, ss.MapDecoder(src, ss.WithCaseSensitiveKeys()))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Name, "")
+	})
+
+	t.Run("should recurse into nested structs via a child MapDecoder", func(t *testing.T) {
+		src := map[string]interface{}{
+			"address": map[string]interface{}{
+				"city": "Springfield",
+			},
+		}
+
+		var output struct {
+			Address struct {
+				City string `map:"city"`
+			} `map:"address"`
+		}
+		err := ss.Decode(&output, ss.MapDecoder(src))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Address.City, "Springfield")
+	})
+
+	t.Run("should populate a slice of structs from a list of maps", func(t *testing.T) {
+		src := map[string]interface{}{
+			"users": []interface{}{
+				map[string]interface{}{"name": "alice"},
+				map[string]interface{}{"name": "bob"},
+			},
+		}
+
+		type User struct {
+			Name string `map:"name"`
+		}
+		var output struct {
+			Users []User `map:"users"`
+		}
+		err := ss.Decode(&output, ss.MapDecoder(src))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Users, []User{
+			{Name: "alice"},
+			{Name: "bob"},
+		})
+	})
+
+	t.Run("should keep successfully decoded elements when one fails with WithIgnoreDecodeErrors", func(t *testing.T) {
+		src := map[string]interface{}{
+			"users": []interface{}{
+				map[string]interface{}{"name": "alice", "age": "not-an-int"},
+				map[string]interface{}{"name": "bob", "age": 30},
+			},
+		}
+
+		type User struct {
+			Name string `map:"name"`
+			Age  int    `map:"age"`
+		}
+		var output struct {
+			Users []User `map:"users"`
+		}
+		err := ss.Decode(&output, ss.MapDecoder(src), ss.WithIgnoreDecodeErrors())
+		tt.AssertErrContains(t, err, "Age")
+		tt.AssertEqual(t, output.Users, []User{
+			{Name: "bob", Age: 30},
+		})
+	})
+
+	t.Run("should leave a slice of structs untouched when the key is missing", func(t *testing.T) {
+		src := map[string]interface{}{}
+
+		type User struct {
+			Name string `map:"name"`
+		}
+		var output struct {
+			Users []User `map:"users"`
+		}
+		err := ss.Decode(&output, ss.MapDecoder(src))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Users, []User(nil))
+	})
+
+	t.Run("should error when a struct field's source value isn't a map", func(t *testing.T) {
+		src := map[string]interface{}{
+			"address": "not-a-map",
+		}
+
+		var output struct {
+			Address struct {
+				City string `map:"city"`
+			} `map:"address"`
+		}
+		err := ss.Decode(&output, ss.MapDecoder(src))
+		tt.AssertErrContains(t, err, "Address")
+	})
+
+	t.Run("should leave the field untouched when the key is missing", func(t *testing.T) {
+		src := map[string]interface{}{}
+
+		var output struct {
+			Name string `map:"name"`
+		}
+		err := ss.Decode(&output, ss.MapDecoder(src))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Name, "")
+	})
+
+	t.Run("with WithWeaklyTypedInput", func(t *testing.T) {
+		t.Run("should coerce a numeric string into an int", func(t *testing.T) {
+			src := map[string]interface{}{"age": "42"}
+
+			var output struct {
+				Age int `map:"age"`
+			}
+			err := ss.Decode(&output, ss.MapDecoder(src, ss.WithWeaklyTypedInput()))
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, output.Age, 42)
+		})
+
+		t.Run("should coerce a number into a string", func(t *testing.T) {
+			src := map[string]interface{}{"age": 42}
+
+			var output struct {
+				Age string `map:"age"`
+			}
+			err := ss.Decode(&output, ss.MapDecoder(src, ss.WithWeaklyTypedInput()))
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, output.Age, "42")
+		})
+
+		t.Run("should coerce a string into a bool", func(t *testing.T) {
+			src := map[string]interface{}{"active": "true"}
+
+			var output struct {
+				Active bool `map:"active"`
+			}
+			err := ss.Decode(&output, ss.MapDecoder(src, ss.WithWeaklyTypedInput()))
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, output.Active, true)
+		})
+
+		t.Run("should wrap a single value into a one-element slice", func(t *testing.T) {
+			src := map[string]interface{}{"tags": "admin"}
+
+			var output struct {
+				Tags []string `map:"tags"`
+			}
+			err := ss.Decode(&output, ss.MapDecoder(src, ss.WithWeaklyTypedInput()))
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, output.Tags, []string{"admin"})
+		})
+	})
+
+	t.Run("with WithMetadata", func(t *testing.T) {
+		t.Run("should mark a slice-of-structs field's own key consumed", func(t *testing.T) {
+			src := map[string]interface{}{
+				"users": []interface{}{
+					map[string]interface{}{"name": "alice"},
+				},
+			}
+
+			type User struct {
+				Name string `map:"name"`
+			}
+			var output struct {
+				Users []User `map:"users"`
+			}
+
+			var metadata ss.Metadata
+			err := ss.Decode(&output, ss.MapDecoder(src, ss.WithMetadata(&metadata)))
+			tt.AssertNoErr(t, err)
+
+			tt.AssertEqual(t, metadata.Keys, []string{"users", "users.0", "users.0.name"})
+			tt.AssertEqual(t, metadata.Unused, []string(nil))
+		})
+
+		t.Run("should report consumed and unused keys", func(t *testing.T) {
+			src := map[string]interface{}{
+				"name": "Alice",
+				"address": map[string]interface{}{
+					"city":    "Springfield",
+					"country": "USA",
+				},
+				"extra": "unused",
+			}
+
+			var output struct {
+				Name    string `map:"name"`
+				Address struct {
+					City string `map:"city"`
+				} `map:"address"`
+			}
+
+			var metadata ss.Metadata
+			err := ss.Decode(&output, ss.MapDecoder(src, ss.WithMetadata(&metadata)))
+			tt.AssertNoErr(t, err)
+
+			tt.AssertEqual(t, metadata.Keys, []string{"address", "address.city", "name"})
+			tt.AssertEqual(t, metadata.Unused, []string{"address.country", "extra"})
+		})
+	})
+}
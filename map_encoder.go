@@ -0,0 +1,34 @@
+package structscanner
+
+import "reflect"
+
+// MapEncoder returns a TagEncoder that writes each field into dst, keyed by
+// its "map" tag (falling back to the field name when the tag is absent).
+// Struct (and pointer-to-struct) fields are written as a nested
+// map[string]interface{}, recursing with a fresh MapEncoder bound to it.
+func MapEncoder(dst map[string]interface{}) TagEncoder {
+	return FuncTagEncoder(func(field Field, value interface{}) (TagEncoder, error) {
+		key := field.Tags["map"]
+		if key == "" {
+			key = field.Name
+		}
+
+		if isStructOrPtrToStruct(field) {
+			if value == nil {
+				return nil, nil
+			}
+
+			sub := map[string]interface{}{}
+			dst[key] = sub
+			return MapEncoder(sub), nil
+		}
+
+		dst[key] = value
+		return nil, nil
+	})
+}
+
+func isStructOrPtrToStruct(field Field) bool {
+	return field.Kind == reflect.Struct ||
+		(field.Kind == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct)
+}
@@ -0,0 +1,578 @@
+// Package structscanner provides a reflection-based engine for decoding
+// (and encoding) Go structs field by field based on their struct tags,
+// so callers can write a single tag-driven decoder instead of a
+// hand-rolled reflect walker for every new source format (env vars, maps,
+// url.Values, CSV rows, ...).
+package structscanner
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Field describes a single struct field as seen by a TagDecoder or
+// TagEncoder, carrying both its parsed tags and its reflection metadata.
+type Field struct {
+	// Name is the field's name as declared on the struct.
+	Name string
+
+	// Tags holds every `key:"value"` pair found on the field's struct tag.
+	Tags map[string]string
+
+	// Kind is the reflect.Kind of the field's type.
+	Kind reflect.Kind
+
+	// Type is the field's reflect.Type.
+	Type reflect.Type
+
+	// IsEmbeded reports whether the field is an embedded (anonymous) field.
+	IsEmbeded bool
+
+	// Index is the field's index, for use with reflect.Value.FieldByIndex.
+	Index []int
+
+	// SliceIndex is set when Decode is probing for the i-th element of a
+	// []Struct (or []*Struct) field, asking the TagDecoder whether a value
+	// exists for it by calling DecodeField once per index, starting at 0,
+	// until one returns (nil, nil) (gorilla/schema style, e.g. the indexed
+	// keys "Users.0.Name", "Users.1.Name", ...). It is -1 otherwise.
+	SliceIndex int
+}
+
+// StructInfo holds the reflected metadata for every exported field of a
+// struct, as produced by GetStructInfo.
+type StructInfo struct {
+	// Type is the (non-pointer) struct type the fields were parsed from.
+	Type reflect.Type
+
+	// Fields holds one entry per exported field, in declaration order.
+	Fields []Field
+}
+
+// GetStructInfo reflects on input and returns the parsed metadata for its
+// exported fields.
+//
+// input may be a struct pointer, a reflect.Type describing a struct, or a
+// reflect.Type describing a pointer to a struct. The result is memoized in
+// the DefaultStructInfoCache (or the cache passed via
+// WithStructInfoCache), so repeated calls for the same struct type skip
+// re-walking its fields and re-parsing its tags.
+func GetStructInfo(input interface{}, opts ...GetStructInfoOption) (StructInfo, error) {
+	cfg := getStructInfoConfig{cache: DefaultStructInfoCache}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	t, err := structTypeOf(input)
+	if err != nil {
+		return StructInfo{}, err
+	}
+
+	return cfg.cache.getOrParse(t, parseStructInfo)
+}
+
+// structTypeOf resolves input down to the (non-pointer) struct reflect.Type
+// it describes, validating the shape GetStructInfo accepts.
+func structTypeOf(input interface{}) (reflect.Type, error) {
+	var t reflect.Type
+	if rt, ok := input.(reflect.Type); ok {
+		t = rt
+	} else {
+		v := reflect.ValueOf(input)
+		if v.Kind() != reflect.Ptr {
+			return nil, fmt.Errorf("expected struct pointer as input, got: %T", input)
+		}
+		if v.IsNil() {
+			return nil, fmt.Errorf("expected non-nil pointer to struct, got: %T", input)
+		}
+		t = v.Type()
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can only get struct info from structs, got: %q", t.String())
+	}
+
+	return t, nil
+}
+
+// parseStructInfo walks t's exported fields, parsing their tags. t must
+// already be a struct type, as returned by structTypeOf.
+func parseStructInfo(t reflect.Type) (StructInfo, error) {
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			// Unexported field, skip it.
+			continue
+		}
+
+		tags, err := parseTags(sf.Name, string(sf.Tag))
+		if err != nil {
+			return StructInfo{}, err
+		}
+
+		fields = append(fields, Field{
+			Name:       sf.Name,
+			Tags:       tags,
+			Kind:       sf.Type.Kind(),
+			Type:       sf.Type,
+			IsEmbeded:  sf.Anonymous,
+			Index:      sf.Index,
+			SliceIndex: -1,
+		})
+	}
+
+	return StructInfo{
+		Type:   t,
+		Fields: fields,
+	}, nil
+}
+
+// parseTags parses a raw struct tag string into a map of key/value pairs,
+// following the same syntax as reflect.StructTag but reporting a
+// descriptive error instead of silently ignoring malformed tags.
+func parseTags(fieldName, tag string) (map[string]string, error) {
+	tags := map[string]string{}
+	orig := tag
+
+	for tag != "" {
+		// Skip leading space.
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// Scan to colon, a space or control character ends the name early.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("malformed tag on field %s: missing name (tag: `%s`)", fieldName, orig)
+		}
+		if i >= len(tag) || tag[i] != ':' {
+			if i < len(tag) {
+				return nil, fmt.Errorf("malformed tag on field %s: unexpected character %d (tag: `%s`)", fieldName, tag[i], orig)
+			}
+			return nil, fmt.Errorf("malformed tag on field %s: missing colon (tag: `%s`)", fieldName, orig)
+		}
+		name := tag[:i]
+		tag = tag[i+1:]
+
+		if tag == "" || tag[0] != '"' {
+			return nil, fmt.Errorf("malformed tag on field %s: missing quotes (tag: `%s`)", fieldName, orig)
+		}
+
+		// Scan quoted value.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return nil, fmt.Errorf("malformed tag on field %s: missing end quote (tag: `%s`)", fieldName, orig)
+		}
+		qvalue := tag[:i+1]
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(qvalue)
+		if err != nil {
+			return nil, fmt.Errorf("malformed tag on field %s: invalid quoted value (tag: `%s`)", fieldName, orig)
+		}
+
+		tags[name] = value
+	}
+
+	return tags, nil
+}
+
+// TagDecoder decodes the value for a single struct field.
+//
+// Returning (nil, nil) leaves the field untouched. Returning a TagDecoder
+// makes Decode recurse into the field as if it were a nested struct, using
+// the returned decoder for its fields. Any other returned value is
+// converted, via reflection, into the field's type.
+type TagDecoder interface {
+	DecodeField(field Field) (interface{}, error)
+}
+
+// FuncTagDecoder adapts a plain function into a TagDecoder.
+type FuncTagDecoder func(field Field) (interface{}, error)
+
+// DecodeField implements the TagDecoder interface.
+func (f FuncTagDecoder) DecodeField(field Field) (interface{}, error) {
+	return f(field)
+}
+
+// DecodeOption configures a single Decode call.
+type DecodeOption func(*decodeConfig)
+
+type decodeConfig struct {
+	converters         *ConverterRegistry
+	requireAllFields   bool
+	zeroEmpty          bool
+	ignoreDecodeErrors bool
+}
+
+// WithConverterRegistry makes Decode consult registry instead of the
+// package-level default one, so tests (and callers that can't share global
+// state) can decode using an isolated set of converters.
+func WithConverterRegistry(registry *ConverterRegistry) DecodeOption {
+	return func(c *decodeConfig) {
+		c.converters = registry
+	}
+}
+
+// WithRequireAllFields makes Decode fail, for each struct it visits
+// (recursing into nested ones), if any of its fields was left untouched,
+// i.e. the decoder returned (nil, nil) for it, or, for a slice-of-structs
+// field, no indexed elements were found at all.
+func WithRequireAllFields() DecodeOption {
+	return func(c *decodeConfig) {
+		c.requireAllFields = true
+	}
+}
+
+// WithZeroEmpty makes Decode write a field's zero value whenever the
+// decoder returns an empty string or an empty (but non-nil) slice for it,
+// instead of assigning that empty value as-is.
+func WithZeroEmpty() DecodeOption {
+	return func(c *decodeConfig) {
+		c.zeroEmpty = true
+	}
+}
+
+// WithIgnoreDecodeErrors makes Decode keep going after a field fails to
+// decode, collecting every such error instead of aborting on the first
+// one. The returned error wraps them all (via Unwrap() []error), so
+// errors.Is/As still finds any one of them.
+func WithIgnoreDecodeErrors() DecodeOption {
+	return func(c *decodeConfig) {
+		c.ignoreDecodeErrors = true
+	}
+}
+
+// ErrMissingFields is wrapped by the error WithRequireAllFields returns,
+// so callers can detect it with errors.Is/As regardless of which fields
+// were missing.
+var ErrMissingFields = errors.New("missing required field(s)")
+
+// Decode fills the exported fields of the struct pointed to by input using
+// values produced by decoder, one field at a time.
+func Decode(input interface{}, decoder TagDecoder, opts ...DecodeOption) error {
+	cfg := decodeConfig{converters: DefaultConverterRegistry}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return decode(input, decoder, &cfg)
+}
+
+func decode(input interface{}, decoder TagDecoder, cfg *decodeConfig) error {
+	structInfo, err := GetStructInfo(input)
+	if err != nil {
+		return err
+	}
+
+	// fail records err instead of aborting decode when cfg.ignoreDecodeErrors
+	// is set, returning nil so the caller knows to keep going; otherwise it
+	// just returns err back to the caller, unchanged.
+	var errs decodeErrors
+	fail := func(err error) error {
+		if cfg.ignoreDecodeErrors {
+			errs = append(errs, err)
+			return nil
+		}
+		return err
+	}
+
+	var missingFields []string
+
+	structVal := reflect.ValueOf(input).Elem()
+	for _, field := range structInfo.Fields {
+		value, err := decoder.DecodeField(field)
+		if err != nil {
+			if err := fail(fmt.Errorf("error decoding field %s: %w", field.Name, err)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fieldVal := structVal.FieldByIndex(field.Index)
+
+		if value == nil {
+			if isSliceOfStructs(field.Type) {
+				if err := decodeIndexedSlice(fieldVal, field, decoder, cfg); err != nil {
+					if err := fail(fmt.Errorf("error decoding field %s: %w", field.Name, err)); err != nil {
+						return err
+					}
+					continue
+				}
+				if fieldVal.Len() > 0 {
+					continue
+				}
+			}
+
+			if cfg.requireAllFields {
+				missingFields = append(missingFields, field.Name)
+			}
+			continue
+		}
+
+		if cfg.zeroEmpty && isEmptyValue(value) {
+			fieldVal.Set(reflect.Zero(fieldVal.Type()))
+			continue
+		}
+
+		if nestedDecoder, ok := value.(TagDecoder); ok {
+			target := fieldVal.Addr()
+			if fieldVal.Kind() == reflect.Ptr && fieldVal.Type().Elem().Kind() == reflect.Struct {
+				if fieldVal.IsNil() {
+					fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+				}
+				target = fieldVal
+			}
+
+			if err := decode(target.Interface(), nestedDecoder, cfg); err != nil {
+				if err := fail(fmt.Errorf("error decoding nested field %s: %w", field.Name, err)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := convertAssign(cfg.converters, field.Name, fieldVal, reflect.ValueOf(value)); err != nil {
+			if err := fail(err); err != nil {
+				return err
+			}
+		}
+	}
+
+	if cfg.requireAllFields && len(missingFields) > 0 {
+		if err := fail(fmt.Errorf("%w: %s", ErrMissingFields, strings.Join(missingFields, ", "))); err != nil {
+			return err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// isEmptyValue reports whether value is an empty (but non-nil) string or
+// slice, the cases WithZeroEmpty treats specially.
+func isEmptyValue(value interface{}) bool {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array:
+		return rv.Len() == 0
+	default:
+		return false
+	}
+}
+
+// decodeErrors collects every per-field error encountered while decoding a
+// single struct, for use with WithIgnoreDecodeErrors. It implements
+// Unwrap() []error so errors.Is/As still finds any one of them.
+type decodeErrors []error
+
+func (e decodeErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e decodeErrors) Unwrap() []error {
+	return []error(e)
+}
+
+// isSliceOfStructs reports whether t is a []Struct or []*Struct, the shapes
+// decodeIndexedSlice knows how to populate element by element.
+func isSliceOfStructs(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct
+}
+
+// decodeIndexedSlice fills fieldVal, a []Struct or []*Struct field, one
+// element at a time by probing decoder with field.SliceIndex set to 0, 1,
+// 2, ... until it reports an index doesn't exist by returning (nil, nil).
+// fieldVal is left untouched if even index 0 doesn't exist.
+//
+// With cfg.ignoreDecodeErrors, a failure decoding one element doesn't
+// abort the whole slice: the element is skipped, its error is collected,
+// and probing continues with the next index, so elements that decoded
+// fine (including ones after the failing index) still end up in fieldVal.
+func decodeIndexedSlice(fieldVal reflect.Value, field Field, decoder TagDecoder, cfg *decodeConfig) error {
+	elemType := field.Type.Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	var elems reflect.Value
+	var errs decodeErrors
+	for i := 0; ; i++ {
+		indexed := field
+		indexed.SliceIndex = i
+
+		value, err := decoder.DecodeField(indexed)
+		if err != nil {
+			return fmt.Errorf("error decoding element %d: %w", i, err)
+		}
+		if value == nil {
+			break
+		}
+
+		nestedDecoder, ok := value.(TagDecoder)
+		if !ok {
+			return fmt.Errorf("error decoding element %d: expected a TagDecoder for a slice-of-structs element, got %T", i, value)
+		}
+
+		structPtr := reflect.New(structType)
+		if err := decode(structPtr.Interface(), nestedDecoder, cfg); err != nil {
+			wrapped := fmt.Errorf("error decoding element %d: %w", i, err)
+			if !cfg.ignoreDecodeErrors {
+				return wrapped
+			}
+			errs = append(errs, wrapped)
+			continue
+		}
+
+		elem := structPtr.Elem()
+		if elemType.Kind() == reflect.Ptr {
+			elem = structPtr
+		}
+
+		if !elems.IsValid() {
+			elems = reflect.MakeSlice(field.Type, 0, 1)
+		}
+		elems = reflect.Append(elems, elem)
+	}
+
+	if elems.IsValid() {
+		fieldVal.Set(elems)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ErrCannotConvert is wrapped by every type-mismatch error convertAssign
+// returns, so callers can detect a conversion failure with errors.Is/As
+// regardless of the exact message.
+var ErrCannotConvert = errors.New("cannot convert value")
+
+// convertAssign converts srcVal into dst's type and assigns it, recursing
+// into pointers and slices the same way Decode would. fieldName is used
+// purely to build descriptive errors (e.g. "Attr1[2]" for a slice element).
+//
+// registry is consulted before the generic reflect-based assignment below,
+// both for the field itself and for each slice element, so user-registered
+// types (time.Time, net.IP, custom enums, ...) never have to go through the
+// generic AssignableTo/ConvertibleTo checks.
+func convertAssign(registry *ConverterRegistry, fieldName string, dst reflect.Value, srcVal reflect.Value) error {
+	if convert, ok := registry.lookup(dst.Type()); ok {
+		var src interface{}
+		if srcVal.IsValid() {
+			src = srcVal.Interface()
+		}
+
+		converted, err := convert(src)
+		if err != nil {
+			return fmt.Errorf("error converting %s: %w", fieldName, err)
+		}
+		dst.Set(converted)
+		return nil
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		if !srcVal.IsValid() {
+			return nil
+		}
+		if srcVal.Type().AssignableTo(dst.Type()) {
+			dst.Set(srcVal)
+			return nil
+		}
+
+		ptr := reflect.New(dst.Type().Elem())
+		if err := convertAssign(registry, fieldName, ptr.Elem(), srcVal); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
+	}
+
+	for srcVal.Kind() == reflect.Ptr {
+		if srcVal.IsNil() {
+			return nil
+		}
+		srcVal = srcVal.Elem()
+	}
+	if !srcVal.IsValid() {
+		return nil
+	}
+
+	if srcVal.CanInterface() {
+		handled, err := tryUnmarshal(dst, srcVal.Interface())
+		if err != nil {
+			return fmt.Errorf("error converting %s: %w", fieldName, err)
+		}
+		if handled {
+			return nil
+		}
+	}
+
+	if dst.Kind() == reflect.Slice {
+		if srcVal.Kind() != reflect.Slice {
+			return fmt.Errorf("expected slice for field %s, got %T: %v", fieldName, srcVal.Interface(), srcVal.Interface())
+		}
+
+		newSlice := reflect.MakeSlice(dst.Type(), srcVal.Len(), srcVal.Len())
+		for i := 0; i < srcVal.Len(); i++ {
+			elem := srcVal.Index(i)
+			if elem.Kind() == reflect.Interface {
+				elem = elem.Elem()
+			}
+			if err := convertAssign(registry, fmt.Sprintf("%s[%d]", fieldName, i), newSlice.Index(i), elem); err != nil {
+				return err
+			}
+		}
+		dst.Set(newSlice)
+		return nil
+	}
+
+	if srcVal.Type().AssignableTo(dst.Type()) {
+		dst.Set(srcVal)
+		return nil
+	}
+	if srcVal.Type().ConvertibleTo(dst.Type()) {
+		dst.Set(srcVal.Convert(dst.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("error converting %s: cannot convert type %s to %s: %w", fieldName, srcVal.Type(), dst.Type(), ErrCannotConvert)
+}
@@ -257,6 +257,90 @@ func TestDecode(t *testing.T) {
 		})
 	})
 
+	t.Run("indexed slices of structs", func(t *testing.T) {
+		type User struct {
+			Name string `env:"name"`
+		}
+
+		t.Run("should populate a slice of structs element by element", func(t *testing.T) {
+			names := []string{"alice", "bob"}
+
+			decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+				if field.SliceIndex < 0 || field.SliceIndex >= len(names) {
+					return nil, nil
+				}
+				name := names[field.SliceIndex]
+				return ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+					return name, nil
+				}), nil
+			})
+
+			var output struct {
+				Users []User
+			}
+			err := ss.Decode(&output, decoder)
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, output.Users, []User{
+				{Name: "alice"},
+				{Name: "bob"},
+			})
+		})
+
+		t.Run("should populate a slice of struct pointers element by element", func(t *testing.T) {
+			names := []string{"alice", "bob"}
+
+			decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+				if field.SliceIndex < 0 || field.SliceIndex >= len(names) {
+					return nil, nil
+				}
+				name := names[field.SliceIndex]
+				return ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+					return name, nil
+				}), nil
+			})
+
+			var output struct {
+				Users []*User
+			}
+			err := ss.Decode(&output, decoder)
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, output.Users, []*User{
+				{Name: "alice"},
+				{Name: "bob"},
+			})
+		})
+
+		t.Run("should leave the field untouched when index 0 doesn't exist", func(t *testing.T) {
+			decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+				return nil, nil
+			})
+
+			var output struct {
+				Users []User
+			}
+			err := ss.Decode(&output, decoder)
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, output.Users, []User(nil))
+		})
+
+		t.Run("should wrap the error returned while decoding an element", func(t *testing.T) {
+			decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+				if field.SliceIndex == 0 {
+					return ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+						return []int{1, 2, 3}, nil
+					}), nil
+				}
+				return nil, nil
+			})
+
+			var output struct {
+				Users []User
+			}
+			err := ss.Decode(&output, decoder)
+			tt.AssertErrContains(t, err, "Users", "element 0")
+		})
+	})
+
 	t.Run("should convert types correctly", func(t *testing.T) {
 		t.Run("should convert different types of integers", func(t *testing.T) {
 			decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
@@ -0,0 +1,60 @@
+package structscanner
+
+import (
+	"reflect"
+	"sync"
+)
+
+// StructInfoCache memoizes the StructInfo produced for each reflect.Type
+// GetStructInfo is asked about, so hot paths (decoding env vars per
+// request, scanning a row per query result, ...) don't re-walk a struct's
+// fields and re-parse its tags on every call.
+//
+// The zero value is ready to use.
+type StructInfoCache struct {
+	infoByType sync.Map // reflect.Type -> StructInfo
+}
+
+// NewStructInfoCache returns an empty, ready to use StructInfoCache. Most
+// callers should rely on DefaultStructInfoCache instead; this is mainly
+// useful for tests that want an isolated cache.
+func NewStructInfoCache() *StructInfoCache {
+	return &StructInfoCache{}
+}
+
+// DefaultStructInfoCache is the cache GetStructInfo consults when no
+// WithStructInfoCache option is passed. It is never invalidated, only
+// populated, for the lifetime of the process.
+var DefaultStructInfoCache = NewStructInfoCache()
+
+func (c *StructInfoCache) getOrParse(t reflect.Type, parse func(reflect.Type) (StructInfo, error)) (StructInfo, error) {
+	if cached, ok := c.infoByType.Load(t); ok {
+		return cached.(StructInfo), nil
+	}
+
+	info, err := parse(t)
+	if err != nil {
+		return StructInfo{}, err
+	}
+
+	// LoadOrStore so concurrent first-time parses of the same type agree on
+	// a single cached StructInfo instead of racing to overwrite it.
+	actual, _ := c.infoByType.LoadOrStore(t, info)
+	return actual.(StructInfo), nil
+}
+
+// GetStructInfoOption configures a single GetStructInfo call.
+type GetStructInfoOption func(*getStructInfoConfig)
+
+type getStructInfoConfig struct {
+	cache *StructInfoCache
+}
+
+// WithStructInfoCache makes GetStructInfo consult cache instead of the
+// DefaultStructInfoCache, so tests (and callers that can't share global
+// state) can reflect using an isolated cache.
+func WithStructInfoCache(cache *StructInfoCache) GetStructInfoOption {
+	return func(c *getStructInfoConfig) {
+		c.cache = cache
+	}
+}
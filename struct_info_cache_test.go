@@ -0,0 +1,84 @@
+package structscanner_test
+
+import (
+	"testing"
+
+	ss "github.com/vingarcia/structscanner"
+	tt "github.com/vingarcia/structscanner/internal/testtools"
+)
+
+type benchStruct struct {
+	Attr1  string `env:"attr1"`
+	Attr2  string `env:"attr2"`
+	Attr3  string `env:"attr3"`
+	Attr4  string `env:"attr4"`
+	Attr5  string `env:"attr5"`
+	Attr6  string `env:"attr6"`
+	Attr7  string `env:"attr7"`
+	Attr8  string `env:"attr8"`
+	Attr9  string `env:"attr9"`
+	Attr10 string `env:"attr10"`
+	Attr11 string `env:"attr11"`
+	Attr12 string `env:"attr12"`
+	Attr13 string `env:"attr13"`
+	Attr14 string `env:"attr14"`
+	Attr15 string `env:"attr15"`
+	Attr16 string `env:"attr16"`
+	Attr17 string `env:"attr17"`
+	Attr18 string `env:"attr18"`
+	Attr19 string `env:"attr19"`
+	Attr20 string `env:"attr20"`
+}
+
+func TestGetStructInfoCache(t *testing.T) {
+	t.Run("should return the same StructInfo on repeated calls", func(t *testing.T) {
+		var s benchStruct
+		first, err := ss.GetStructInfo(&s)
+		tt.AssertNoErr(t, err)
+
+		second, err := ss.GetStructInfo(&s)
+		tt.AssertNoErr(t, err)
+
+		tt.AssertEqual(t, first, second)
+	})
+
+	t.Run("should compute equal StructInfo in isolated caches", func(t *testing.T) {
+		var s benchStruct
+
+		cache1 := ss.NewStructInfoCache()
+		info1, err := ss.GetStructInfo(&s, ss.WithStructInfoCache(cache1))
+		tt.AssertNoErr(t, err)
+
+		cache2 := ss.NewStructInfoCache()
+		info2, err := ss.GetStructInfo(&s, ss.WithStructInfoCache(cache2))
+		tt.AssertNoErr(t, err)
+
+		// Equal content, but independently computed/cached.
+		tt.AssertEqual(t, info1, info2)
+	})
+}
+
+// BenchmarkGetStructInfoCold parses benchStruct's tags from scratch on
+// every call, by handing GetStructInfo a fresh, empty cache each time.
+func BenchmarkGetStructInfoCold(b *testing.B) {
+	var s benchStruct
+	for i := 0; i < b.N; i++ {
+		_, err := ss.GetStructInfo(&s, ss.WithStructInfoCache(ss.NewStructInfoCache()))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGetStructInfoWarm reuses a single cache across every call, so
+// only the first iteration actually parses benchStruct's tags.
+func BenchmarkGetStructInfoWarm(b *testing.B) {
+	var s benchStruct
+	cache := ss.NewStructInfoCache()
+	for i := 0; i < b.N; i++ {
+		_, err := ss.GetStructInfo(&s, ss.WithStructInfoCache(cache))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
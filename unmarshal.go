@@ -0,0 +1,50 @@
+package structscanner
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+)
+
+// tryUnmarshal checks whether a pointer to dst implements json.Unmarshaler,
+// encoding.TextUnmarshaler or encoding.BinaryUnmarshaler and, if src has a
+// shape one of those interfaces understands, uses it instead of the generic
+// reflect-based assignment. A string or []byte source is only ever handed to
+// TextUnmarshaler/BinaryUnmarshaler, since feeding raw, unquoted bytes to
+// UnmarshalJSON would fail for anything but a JSON number; json.RawMessage
+// is the only source routed to json.Unmarshaler. It reports whether dst was
+// handled this way, so types like time.Time, net.IP or a user-defined enum
+// decode without requiring a ConverterRegistry entry.
+func tryUnmarshal(dst reflect.Value, src interface{}) (bool, error) {
+	if !dst.CanAddr() {
+		return false, nil
+	}
+
+	addr := dst.Addr()
+	if !addr.CanInterface() {
+		return false, nil
+	}
+	target := addr.Interface()
+
+	if raw, ok := src.(json.RawMessage); ok {
+		if u, ok := target.(json.Unmarshaler); ok {
+			return true, u.UnmarshalJSON(raw)
+		}
+	}
+
+	switch v := src.(type) {
+	case string:
+		if u, ok := target.(encoding.TextUnmarshaler); ok {
+			return true, u.UnmarshalText([]byte(v))
+		}
+	case []byte:
+		if u, ok := target.(encoding.TextUnmarshaler); ok {
+			return true, u.UnmarshalText(v)
+		}
+		if u, ok := target.(encoding.BinaryUnmarshaler); ok {
+			return true, u.UnmarshalBinary(v)
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,134 @@
+package structscanner_test
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	ss "github.com/vingarcia/structscanner"
+	tt "github.com/vingarcia/structscanner/internal/testtools"
+)
+
+func TestDecodeUnmarshalerInterfaces(t *testing.T) {
+	t.Run("should decode a time.Time from a string via encoding.TextUnmarshaler", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "2022-01-02T15:04:05Z", nil
+		})
+
+		var output struct {
+			Attr1 time.Time `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC))
+	})
+
+	t.Run("should decode a net.IP from a string via encoding.TextUnmarshaler", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "127.0.0.1", nil
+		})
+
+		var output struct {
+			Attr1 net.IP `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, net.ParseIP("127.0.0.1"))
+	})
+
+	t.Run("should decode a pointer field via the same interfaces", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "2022-01-02T15:04:05Z", nil
+		})
+
+		var output struct {
+			Attr1 *time.Time `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertNotEqual(t, output.Attr1, nil)
+		tt.AssertEqual(t, *output.Attr1, time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC))
+	})
+
+	t.Run("should work with a json.RawMessage source", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return json.RawMessage(`"2022-01-02T15:04:05Z"`), nil
+		})
+
+		var output struct {
+			Attr1 time.Time `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC))
+	})
+
+	t.Run("should fire for slice elements", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return []string{"2022-01-02T15:04:05Z", "2023-05-06T07:08:09Z"}, nil
+		})
+
+		var output struct {
+			Attr1 []time.Time `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, []time.Time{
+			time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC),
+			time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC),
+		})
+	})
+
+	t.Run("should not feed a raw string to json.Unmarshaler", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "active", nil
+		})
+
+		var output struct {
+			Attr1 jsonEnum `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, jsonEnum("active"))
+	})
+
+	t.Run("should still use json.Unmarshaler for a json.RawMessage source", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return json.RawMessage(`"active"`), nil
+		})
+
+		var output struct {
+			Attr1 jsonEnum `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, jsonEnum("active"))
+	})
+
+	t.Run("should wrap the error returned by the unmarshaler", func(t *testing.T) {
+		decoder := ss.FuncTagDecoder(func(field ss.Field) (interface{}, error) {
+			return "not-a-time", nil
+		})
+
+		var output struct {
+			Attr1 time.Time `env:"attr1"`
+		}
+		err := ss.Decode(&output, decoder)
+		tt.AssertErrContains(t, err, "Attr1")
+	})
+}
+
+// jsonEnum implements only json.Unmarshaler, the way a typical custom enum
+// type does, to check that a plain (unquoted) string source is never fed
+// to it directly.
+type jsonEnum string
+
+func (e *jsonEnum) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*e = jsonEnum(s)
+	return nil
+}
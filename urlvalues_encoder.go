@@ -0,0 +1,36 @@
+package structscanner
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// URLValuesEncoder returns a TagEncoder that writes each scalar field into
+// dst as a string, keyed by its "url" tag (falling back to the field name).
+// Struct (and pointer-to-struct) fields recurse into a nested
+// URLValuesEncoder whose keys are prefixed with "<field>.", e.g.
+// "Address.City".
+func URLValuesEncoder(dst url.Values) TagEncoder {
+	return urlValuesEncoder(dst, "")
+}
+
+func urlValuesEncoder(dst url.Values, prefix string) TagEncoder {
+	return FuncTagEncoder(func(field Field, value interface{}) (TagEncoder, error) {
+		key := field.Tags["url"]
+		if key == "" {
+			key = field.Name
+		}
+		key = prefix + key
+
+		if isStructOrPtrToStruct(field) {
+			if value == nil {
+				return nil, nil
+			}
+
+			return urlValuesEncoder(dst, key+"."), nil
+		}
+
+		dst.Set(key, fmt.Sprintf("%v", value))
+		return nil, nil
+	})
+}